@@ -0,0 +1,230 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+var (
+	latencyMode        = flag.Bool("latency", false, "Repeatedly PING the server and report running min/avg/max/stddev latency, like upstream redis-cli --latency")
+	latencyHistoryMode = flag.Bool("latency-history", false, "Like --latency, but print one summary line every -i seconds instead of continuously redrawing")
+	latencyDistMode    = flag.Bool("latency-dist", false, "Sample PING round-trip times and draw an ASCII histogram of their distribution")
+	latencyInterval    = flag.Int("i", 1, "Interval in seconds between summary lines, used with --latency-history")
+)
+
+// latencyStats accumulates running min/max/avg/stddev over a stream of
+// samples, using Welford's online algorithm so memory stays constant no
+// matter how long the sampler runs.
+type latencyStats struct {
+	count    int64
+	min, max time.Duration
+	mean     float64
+	m2       float64
+}
+
+func (s *latencyStats) add(d time.Duration) {
+	s.count++
+	if s.count == 1 || d < s.min {
+		s.min = d
+	}
+	if s.count == 1 || d > s.max {
+		s.max = d
+	}
+
+	// Welford's method for a numerically stable running mean/variance.
+	x := float64(d.Microseconds()) / 1000.0
+	delta := x - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (x - s.mean)
+}
+
+func (s *latencyStats) stddev() float64 {
+	if s.count < 2 {
+		return 0
+	}
+	return math.Sqrt(s.m2 / float64(s.count))
+}
+
+func (s *latencyStats) String() string {
+	return fmt.Sprintf("min: %.2f, max: %.2f, avg: %.2f (%d samples), stddev: %.2f",
+		float64(s.min.Microseconds())/1000.0, float64(s.max.Microseconds())/1000.0, s.mean, s.count, s.stddev())
+}
+
+// onInterrupt returns a channel that receives once Ctrl-C is pressed, for
+// the --latency* loops to select on.
+func onInterrupt() chan os.Signal {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	return stop
+}
+
+func pingRTT() (time.Duration, error) {
+	start := time.Now()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// runLatency implements --latency: ping continuously and redraw a running
+// min/avg/max/stddev summary in place until Ctrl-C, then print it one last
+// time.
+func runLatency() int {
+	stop := onInterrupt()
+	stats := &latencyStats{}
+
+	for {
+		select {
+		case <-stop:
+			fmt.Println()
+			fmt.Println(stats.String())
+			return 0
+		default:
+		}
+
+		d, err := pingRTT()
+		if err != nil {
+			fmt.Printf("\n(error) %s\n", err.Error())
+			return 1
+		}
+		stats.add(d)
+		fmt.Printf("\r%s", stats.String())
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// runLatencyHistory implements --latency-history: print one summary line
+// every -i seconds instead of redrawing in place. The stats are reset after
+// each line, so every line covers only the samples taken since the last one.
+func runLatencyHistory() int {
+	stop := onInterrupt()
+	interval := time.Duration(*latencyInterval) * time.Second
+	stats := &latencyStats{}
+	deadline := time.Now().Add(interval)
+
+	for {
+		select {
+		case <-stop:
+			fmt.Println(stats.String())
+			return 0
+		default:
+		}
+
+		d, err := pingRTT()
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return 1
+		}
+		stats.add(d)
+
+		if time.Now().After(deadline) {
+			fmt.Println(stats.String())
+			stats = &latencyStats{}
+			deadline = time.Now().Add(interval)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// latencyBuckets are the upper bound, in milliseconds, of each histogram
+// bucket on a log2 scale: 0-1ms, 1-2ms, 2-4ms, 4-8ms, ...
+var latencyBuckets = []float64{1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024, math.MaxFloat64}
+
+func bucketFor(ms float64) int {
+	for i, upper := range latencyBuckets {
+		if ms <= upper {
+			return i
+		}
+	}
+	return len(latencyBuckets) - 1
+}
+
+func bucketLabel(i int) string {
+	lower := 0.0
+	if i > 0 {
+		lower = latencyBuckets[i-1]
+	}
+	if i == len(latencyBuckets)-1 {
+		return fmt.Sprintf("%.0fms+", lower)
+	}
+	return fmt.Sprintf("%.0f-%.0fms", lower, latencyBuckets[i])
+}
+
+// runLatencyDist implements --latency-dist: sample PING RTTs, bucket them
+// on the log2 scale in latencyBuckets, and redraw an ASCII histogram in
+// place using ANSI cursor movement until Ctrl-C.
+func runLatencyDist() int {
+	stop := onInterrupt()
+	counts := make([]int64, len(latencyBuckets))
+	stats := &latencyStats{}
+	lines := 0
+
+	const barWidth = 50
+
+	for {
+		select {
+		case <-stop:
+			fmt.Println()
+			fmt.Println(stats.String())
+			return 0
+		default:
+		}
+
+		d, err := pingRTT()
+		if err != nil {
+			fmt.Printf("\n(error) %s\n", err.Error())
+			return 1
+		}
+		stats.add(d)
+		ms := float64(d.Microseconds()) / 1000.0
+		counts[bucketFor(ms)]++
+
+		if lines > 0 {
+			fmt.Printf("\033[%dA", lines)
+		}
+		lines = 0
+
+		var maxCount int64
+		for _, c := range counts {
+			if c > maxCount {
+				maxCount = c
+			}
+		}
+
+		for i, c := range counts {
+			if c == 0 && maxCount == 0 {
+				continue
+			}
+			barLen := 0
+			if maxCount > 0 {
+				barLen = int(float64(c) / float64(maxCount) * barWidth)
+			}
+			fmt.Printf("\033[K%-10s |\033[32m%s\033[0m (%d)\n", bucketLabel(i), strings.Repeat("#", barLen), c)
+			lines++
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func runLatencySubcommand() {
+	if *latencyMode {
+		cliConnect()
+		os.Exit(runLatency())
+	}
+	if *latencyHistoryMode {
+		cliConnect()
+		os.Exit(runLatencyHistory())
+	}
+	if *latencyDistMode {
+		cliConnect()
+		os.Exit(runLatencyDist())
+	}
+}
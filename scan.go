@@ -0,0 +1,152 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	scanMode  = flag.Bool("scan", false, "Iterate the keyspace with SCAN and print each key on its own line, then exit")
+	bigkeys   = flag.Bool("bigkeys", false, "Sample keys with SCAN and report the biggest key per TYPE")
+	pattern   = flag.String("pattern", "*", "Key pattern passed to SCAN's MATCH option, used with --scan and --bigkeys")
+	scanCount = flag.Int64("count", 100, "Hint for the number of keys SCAN should return per call, used with --scan and --bigkeys")
+)
+
+// runScan implements --scan: stream every key matching --pattern, one per
+// line, using SCAN so the keyspace can be walked without blocking the
+// server the way KEYS would.
+func runScan() int {
+	iter := client.Scan(ctx, 0, *pattern, *scanCount).Iterator()
+	for iter.Next(ctx) {
+		fmt.Println(iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return 1
+	}
+	return 0
+}
+
+type bigkeyStat struct {
+	keyType string
+	key     string
+	bytes   int64
+}
+
+// runBigkeys implements --bigkeys: walk the keyspace with SCAN, measure
+// each key with MEMORY USAGE, and report the largest key seen per TYPE.
+// MEMORY USAGE is preferred over DEBUG OBJECT since it works against
+// managed/proxy deployments that disable DEBUG.
+func runBigkeys() int {
+	biggest := map[string]bigkeyStat{}
+
+	iter := client.Scan(ctx, 0, *pattern, *scanCount).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+
+		keyType, err := client.Type(ctx, key).Result()
+		if err != nil {
+			continue
+		}
+
+		usage, err := client.MemoryUsage(ctx, key).Result()
+		if err != nil {
+			continue
+		}
+
+		if cur, ok := biggest[keyType]; !ok || usage > cur.bytes {
+			biggest[keyType] = bigkeyStat{keyType: keyType, key: key, bytes: usage}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return 1
+	}
+
+	types := make([]string, 0, len(biggest))
+	for t := range biggest {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	for _, t := range types {
+		stat := biggest[t]
+		fmt.Printf("Biggest %-8s found so far '%s' with %d bytes\n", stat.keyType, stat.key, stat.bytes)
+	}
+	return 0
+}
+
+// delByPattern deletes every key matching pattern, walking the keyspace
+// with SCAN and pipelining DEL in batches of batchSize so a large match set
+// doesn't round-trip one command at a time.
+func delByPattern(pattern string, batchSize int) (int, error) {
+	deleted := 0
+	batch := make([]string, 0, batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		pipe := client.Pipeline()
+		dels := make([]*redis.IntCmd, len(batch))
+		for i, key := range batch {
+			dels[i] = pipe.Del(ctx, key)
+		}
+		_, err := pipe.Exec(ctx)
+		for _, d := range dels {
+			deleted += int(d.Val())
+		}
+		batch = batch[:0]
+		return err
+	}
+
+	iter := client.Scan(ctx, 0, pattern, *scanCount).Iterator()
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				return deleted, err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return deleted, err
+	}
+	if err := flush(); err != nil {
+		return deleted, err
+	}
+
+	return deleted, nil
+}
+
+// cliDelByPattern implements the REPL's `del-by-pattern <pattern>`
+// meta-command.
+func cliDelByPattern(args []string) {
+	if len(args) != 1 {
+		fmt.Println("invalid args. Should be del-by-pattern <pattern>")
+		return
+	}
+
+	n, err := delByPattern(args[0], int(*scanCount))
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	fmt.Printf("(integer) %s\n", strconv.Itoa(n))
+}
+
+func runScanSubcommand() {
+	if *scanMode {
+		cliConnect()
+		os.Exit(runScan())
+	}
+	if *bigkeys {
+		cliConnect()
+		os.Exit(runBigkeys())
+	}
+}